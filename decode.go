@@ -0,0 +1,226 @@
+package godotenv
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Decode binds values onto the exported fields of dst, a pointer to a
+// struct, using `env:"NAME"` tags. String values are converted to each
+// field's type (int/uint/float kinds, bool, time.Duration, []string, or
+// string itself). When comments is non-nil it is run through
+// GenerateSpecsFromComments first, and any Required or invalid spec for a
+// tagged field causes Decode to fail before the value is ever converted.
+func Decode(dst any, values map[string]string, comments map[string]string) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("godotenv: Decode requires a non-nil pointer to a struct")
+	}
+
+	elem := rv.Elem()
+	t := elem.Type()
+
+	var specs Specs
+	if comments != nil {
+		// Seed every tagged field's name into the map passed to
+		// GenerateSpecsFromComments, even when it's absent from values, so
+		// a Required spec for a field that's missing entirely (not just
+		// present-but-empty) still gets generated.
+		specs = GenerateSpecsFromComments(withTaggedFieldNames(t, values), comments)
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			// unexported: reflect can't set it, and there's no tag a
+			// caller outside the package could have meant to reach.
+			continue
+		}
+
+		tag := field.Tag.Get("env")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+		value, ok := values[name]
+
+		if spec, hasSpec := specs[name]; hasSpec {
+			if spec.Required && strings.TrimSpace(value) == "" {
+				return fmt.Errorf("godotenv: field %q is required", name)
+			}
+			if value != "" && !spec.Valid {
+				msg := spec.Error
+				if msg == "" {
+					msg = fmt.Sprintf("failed %s validation", spec.Name)
+				}
+				return fmt.Errorf("godotenv: field %q: %s", name, msg)
+			}
+		}
+
+		if !ok {
+			continue
+		}
+
+		if err := setFieldValue(elem.Field(i), value); err != nil {
+			return fmt.Errorf("godotenv: field %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// withTaggedFieldNames returns a copy of values with an empty entry added
+// for every env-tagged field of t that values doesn't already have one for.
+func withTaggedFieldNames(t reflect.Type, values map[string]string) map[string]string {
+	out := make(map[string]string, len(values))
+	for k, v := range values {
+		out[k] = v
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := field.Tag.Get("env")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+		if _, ok := out[name]; !ok {
+			out[name] = ""
+		}
+	}
+
+	return out
+}
+
+// Encode reads the exported, `env`-tagged fields of src (a struct or
+// pointer to struct) back into a key/value map suitable for Marshal.
+func Encode(src any) (map[string]string, error) {
+	rv := reflect.ValueOf(src)
+	for rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("godotenv: Encode requires a struct or pointer to a struct")
+	}
+
+	t := rv.Type()
+	out := make(map[string]string)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := field.Tag.Get("env")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+		value, err := formatFieldValue(rv.Field(i))
+		if err != nil {
+			return nil, fmt.Errorf("godotenv: field %q: %w", name, err)
+		}
+
+		out[name] = value
+	}
+
+	return out, nil
+}
+
+// setFieldValue converts value and assigns it to fv according to fv's kind.
+func setFieldValue(fv reflect.Value, value string) error {
+	if fv.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(d))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(n)
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported slice element type %s", fv.Type().Elem())
+		}
+		parts := strings.Split(value, ",")
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		fv.Set(reflect.ValueOf(parts))
+	default:
+		return fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+
+	return nil
+}
+
+// formatFieldValue renders fv back to its string representation for Encode.
+func formatFieldValue(fv reflect.Value) (string, error) {
+	if fv.Type() == durationType {
+		return time.Duration(fv.Int()).String(), nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		return fv.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(fv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(fv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(fv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(fv.Float(), 'g', -1, 64), nil
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() != reflect.String {
+			return "", fmt.Errorf("unsupported slice element type %s", fv.Type().Elem())
+		}
+		parts := make([]string, fv.Len())
+		for i := range parts {
+			parts[i] = fv.Index(i).String()
+		}
+		return strings.Join(parts, ","), nil
+	default:
+		return "", fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+}
@@ -0,0 +1,133 @@
+package godotenv
+
+import (
+	"testing"
+	"time"
+)
+
+type decodeTestConfig struct {
+	Port     int           `env:"PORT"`
+	Name     string        `env:"NAME"`
+	Debug    bool          `env:"DEBUG"`
+	Timeout  time.Duration `env:"TIMEOUT"`
+	Tags     []string      `env:"TAGS"`
+	unexport string        `env:"UNEXPORTED"`
+	Ignored  string        `env:"-"`
+}
+
+func TestDecodeConvertsFieldTypes(t *testing.T) {
+	values := map[string]string{
+		"PORT":    "8080",
+		"NAME":    "svc",
+		"DEBUG":   "true",
+		"TIMEOUT": "1500ms",
+		"TAGS":    "a, b,c",
+	}
+
+	var cfg decodeTestConfig
+	if err := Decode(&cfg, values, nil); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	if cfg.Port != 8080 {
+		t.Errorf("Port = %d, want 8080", cfg.Port)
+	}
+	if cfg.Name != "svc" {
+		t.Errorf("Name = %q, want svc", cfg.Name)
+	}
+	if !cfg.Debug {
+		t.Errorf("Debug = false, want true")
+	}
+	if cfg.Timeout != 1500*time.Millisecond {
+		t.Errorf("Timeout = %v, want 1500ms", cfg.Timeout)
+	}
+	want := []string{"a", "b", "c"}
+	if len(cfg.Tags) != len(want) {
+		t.Fatalf("Tags = %v, want %v", cfg.Tags, want)
+	}
+	for i := range want {
+		if cfg.Tags[i] != want[i] {
+			t.Errorf("Tags[%d] = %q, want %q", i, cfg.Tags[i], want[i])
+		}
+	}
+}
+
+func TestDecodeSkipsUnexportedFields(t *testing.T) {
+	var cfg decodeTestConfig
+	if err := Decode(&cfg, map[string]string{"UNEXPORTED": "x"}, nil); err != nil {
+		t.Fatalf("Decode should not error on an unexported tagged field: %v", err)
+	}
+	if cfg.unexport != "" {
+		t.Errorf("unexported field should not be set, got %q", cfg.unexport)
+	}
+}
+
+func TestDecodeRequiredFieldMissingEntirely(t *testing.T) {
+	type cfg struct {
+		Port string `env:"PORT"`
+	}
+
+	var c cfg
+	err := Decode(&c, map[string]string{}, map[string]string{"PORT": "Plain!"})
+	if err == nil {
+		t.Fatal("expected an error for a required field absent from values")
+	}
+}
+
+func TestDecodeRequiredFieldPresentButEmpty(t *testing.T) {
+	type cfg struct {
+		Port string `env:"PORT"`
+	}
+
+	var c cfg
+	err := Decode(&c, map[string]string{"PORT": ""}, map[string]string{"PORT": "Plain!"})
+	if err == nil {
+		t.Fatal("expected an error for a required field present but empty")
+	}
+}
+
+func TestDecodeNumberSpecOutOfRange(t *testing.T) {
+	type cfg struct {
+		Port string `env:"PORT"`
+	}
+
+	var c cfg
+	err := Decode(&c, map[string]string{"PORT": "99999"}, map[string]string{"PORT": `Number:{"min":1,"max":65535}`})
+	if err == nil {
+		t.Fatal("expected an error for an out-of-range Number spec")
+	}
+}
+
+func TestEncodeRoundTripsWithDecode(t *testing.T) {
+	in := decodeTestConfig{
+		Port:    8080,
+		Name:    "svc",
+		Debug:   true,
+		Timeout: 2 * time.Second,
+		Tags:    []string{"a", "b"},
+	}
+
+	values, err := Encode(&in)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	var out decodeTestConfig
+	if err := Decode(&out, values, nil); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	if out.Port != in.Port || out.Name != in.Name || out.Debug != in.Debug || out.Timeout != in.Timeout {
+		t.Errorf("round-trip mismatch: got %+v, want %+v", out, in)
+	}
+	if len(out.Tags) != len(in.Tags) {
+		t.Fatalf("Tags round-trip mismatch: got %v, want %v", out.Tags, in.Tags)
+	}
+}
+
+func TestDecodeRequiresPointerToStruct(t *testing.T) {
+	var notAPointer decodeTestConfig
+	if err := Decode(notAPointer, nil, nil); err == nil {
+		t.Fatal("expected an error when dst is not a pointer")
+	}
+}
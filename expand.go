@@ -0,0 +1,201 @@
+package godotenv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LookupFunc resolves a variable name to a value when the name is not
+// present in the map passed to expansion, as os.LookupEnv does. It lets
+// expansion fall back to the process environment (or any other source)
+// when a key isn't defined locally.
+type LookupFunc func(string) (string, bool)
+
+// ExpandOptions configures variable expansion.
+type ExpandOptions struct {
+	// Lookup is consulted for names not found in the local vars map.
+	Lookup LookupFunc
+}
+
+// expandVariables expands $NAME and ${NAME} references in v, resolving
+// names against vars and, when opts.Lookup is set, falling back to it for
+// names vars doesn't have. It also understands the compose-spec/POSIX
+// parameter expansion operators:
+//
+//	${NAME:-default}  use default if NAME is unset or empty
+//	${NAME-default}   use default if NAME is unset
+//	${NAME:+alt}       use alt if NAME is set and non-empty
+//	${NAME+alt}        use alt if NAME is set
+//	${NAME:?err}       error if NAME is unset or empty
+//	${NAME?err}        error if NAME is unset
+//
+// A literal "\$" is unescaped to "$" without expansion, and "$(" is passed
+// through untouched so command substitution text survives.
+func expandVariables(v string, vars map[string]string, opts ExpandOptions) (string, error) {
+	var b strings.Builder
+	b.Grow(len(v))
+
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+
+		if c == '\\' && i+1 < len(v) && v[i+1] == '$' {
+			b.WriteByte('$')
+			i++
+			continue
+		}
+
+		if c != '$' || i+1 >= len(v) {
+			b.WriteByte(c)
+			continue
+		}
+
+		if v[i+1] == '(' {
+			// command substitution: passed through untouched.
+			b.WriteByte(c)
+			continue
+		}
+
+		if v[i+1] == '{' {
+			end := strings.IndexByte(v[i+2:], '}')
+			if end == -1 {
+				// unterminated ${: treat the rest of the string literally.
+				b.WriteString(v[i:])
+				break
+			}
+			end += i + 2
+
+			expanded, err := expandBraced(v[i+2:end], vars, opts)
+			if err != nil {
+				return "", err
+			}
+
+			b.WriteString(expanded)
+			i = end
+			continue
+		}
+
+		name, length := scanVarName(v[i+1:])
+		if length == 0 {
+			b.WriteByte(c)
+			continue
+		}
+
+		b.WriteString(lookupVar(name, vars, opts))
+		i += length
+	}
+
+	return b.String(), nil
+}
+
+// scanVarName reads a variable name (letters, digits, underscore) from the
+// start of s and returns it along with its byte length.
+func scanVarName(s string) (string, int) {
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		isLetter := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		isDigit := c >= '0' && c <= '9'
+		if !isLetter && !isDigit {
+			break
+		}
+		i++
+	}
+	return s[:i], i
+}
+
+// expandBraced implements the body of a ${...} expansion, dispatching on
+// the :-, -, :+, +, :?, ? operators when present.
+func expandBraced(body string, vars map[string]string, opts ExpandOptions) (string, error) {
+	name, op, arg, hasOp := splitOperator(body)
+	value, ok := lookupVarOK(name, vars, opts)
+
+	if !hasOp {
+		return value, nil
+	}
+
+	switch op {
+	case ":-":
+		if !ok || value == "" {
+			return expandVariables(arg, vars, opts)
+		}
+		return value, nil
+	case "-":
+		if !ok {
+			return expandVariables(arg, vars, opts)
+		}
+		return value, nil
+	case ":+":
+		if ok && value != "" {
+			return expandVariables(arg, vars, opts)
+		}
+		return "", nil
+	case "+":
+		if ok {
+			return expandVariables(arg, vars, opts)
+		}
+		return "", nil
+	case ":?":
+		if !ok || value == "" {
+			return "", fmt.Errorf("%s: %s", name, nonEmpty(arg, "parameter is empty or not set"))
+		}
+		return value, nil
+	case "?":
+		if !ok {
+			return "", fmt.Errorf("%s: %s", name, nonEmpty(arg, "parameter not set"))
+		}
+		return value, nil
+	default:
+		return value, nil
+	}
+}
+
+// nonEmpty returns s, or fallback when s is empty.
+func nonEmpty(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+// splitOperator splits a ${...} body into its variable name and, if
+// present, one of the :-, -, :+, +, :?, ? operators and its argument. The
+// first operator token encountered while scanning left to right wins,
+// matching POSIX parameter expansion.
+func splitOperator(body string) (name, op, arg string, hasOp bool) {
+	twoChar := [...]string{":-", ":+", ":?"}
+	oneChar := [...]string{"-", "+", "?"}
+
+	for i := 0; i < len(body); i++ {
+		for _, o := range twoChar {
+			if strings.HasPrefix(body[i:], o) {
+				return body[:i], o, body[i+len(o):], true
+			}
+		}
+		for _, o := range oneChar {
+			if strings.HasPrefix(body[i:], o) {
+				return body[:i], o, body[i+len(o):], true
+			}
+		}
+	}
+
+	return body, "", "", false
+}
+
+// lookupVarOK resolves name against vars, then opts.Lookup.
+func lookupVarOK(name string, vars map[string]string, opts ExpandOptions) (string, bool) {
+	if v, ok := vars[name]; ok {
+		return v, true
+	}
+	if opts.Lookup != nil {
+		if v, ok := opts.Lookup(name); ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// lookupVar is lookupVarOK without the ok flag, for plain $NAME expansion.
+func lookupVar(name string, vars map[string]string, opts ExpandOptions) string {
+	v, _ := lookupVarOK(name, vars, opts)
+	return v
+}
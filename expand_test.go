@@ -0,0 +1,123 @@
+package godotenv
+
+import "testing"
+
+func TestExpandVariablesPlain(t *testing.T) {
+	vars := map[string]string{"FOO": "bar"}
+
+	cases := map[string]string{
+		"$FOO":       "bar",
+		"${FOO}":     "bar",
+		"$FOObaz":    "",
+		"${FOO}baz":  "barbaz",
+		"no vars":    "no vars",
+		`\$FOO`:      "$FOO",
+		"$(echo hi)": "$(echo hi)",
+		"$MISSING":   "",
+		"${MISSING}": "",
+	}
+
+	for in, want := range cases {
+		got, err := expandVariables(in, vars, ExpandOptions{})
+		if err != nil {
+			t.Fatalf("expandVariables(%q): %v", in, err)
+		}
+		if got != want {
+			t.Errorf("expandVariables(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestExpandVariablesOperators(t *testing.T) {
+	set := map[string]string{"SET": "value"}
+	empty := map[string]string{"EMPTY": ""}
+
+	cases := []struct {
+		expr string
+		vars map[string]string
+		want string
+	}{
+		{"${UNSET:-default}", nil, "default"},
+		{"${EMPTY:-default}", empty, "default"},
+		{"${SET:-default}", set, "value"},
+		{"${UNSET-default}", nil, "default"},
+		{"${EMPTY-default}", empty, ""},
+		{"${SET:+alt}", set, "alt"},
+		{"${EMPTY:+alt}", empty, ""},
+		{"${SET+alt}", set, "alt"},
+		{"${UNSET+alt}", nil, ""},
+	}
+
+	for _, c := range cases {
+		got, err := expandVariables(c.expr, c.vars, ExpandOptions{})
+		if err != nil {
+			t.Fatalf("expandVariables(%q): %v", c.expr, err)
+		}
+		if got != c.want {
+			t.Errorf("expandVariables(%q) = %q, want %q", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestExpandVariablesRequiredOperatorsError(t *testing.T) {
+	if _, err := expandVariables("${UNSET:?must be set}", nil, ExpandOptions{}); err == nil {
+		t.Error("expected an error for ${UNSET:?must be set}")
+	}
+
+	if _, err := expandVariables("${EMPTY:?must be set}", map[string]string{"EMPTY": ""}, ExpandOptions{}); err == nil {
+		t.Error("expected an error for ${EMPTY:?must be set} when EMPTY is empty")
+	}
+
+	if _, err := expandVariables("${UNSET?not set}", nil, ExpandOptions{}); err == nil {
+		t.Error("expected an error for ${UNSET?not set}")
+	}
+
+	// ${EMPTY?...} only errors when the name is entirely unset, not merely empty.
+	got, err := expandVariables("${EMPTY?not set}", map[string]string{"EMPTY": ""}, ExpandOptions{})
+	if err != nil {
+		t.Fatalf("expandVariables(${EMPTY?not set}): %v", err)
+	}
+	if got != "" {
+		t.Errorf("expandVariables(${EMPTY?not set}) = %q, want empty string", got)
+	}
+}
+
+func TestExpandVariablesLookupFallback(t *testing.T) {
+	opts := ExpandOptions{
+		Lookup: func(name string) (string, bool) {
+			if name == "FALLBACK" {
+				return "from-lookup", true
+			}
+			return "", false
+		},
+	}
+
+	got, err := expandVariables("$FALLBACK", nil, opts)
+	if err != nil {
+		t.Fatalf("expandVariables: %v", err)
+	}
+	if got != "from-lookup" {
+		t.Errorf("got %q, want from-lookup", got)
+	}
+
+	// vars takes priority over Lookup.
+	got, err = expandVariables("$FALLBACK", map[string]string{"FALLBACK": "local"}, opts)
+	if err != nil {
+		t.Fatalf("expandVariables: %v", err)
+	}
+	if got != "local" {
+		t.Errorf("got %q, want local (vars should win over Lookup)", got)
+	}
+}
+
+func TestScanVarName(t *testing.T) {
+	name, n := scanVarName("FOO_bar1 baz")
+	if name != "FOO_bar1" || n != len(name) {
+		t.Errorf("scanVarName = %q, %d; want FOO_bar1", name, n)
+	}
+
+	name, n = scanVarName(" leading space")
+	if name != "" || n != 0 {
+		t.Errorf("scanVarName on non-name start = %q, %d; want empty", name, n)
+	}
+}
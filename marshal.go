@@ -0,0 +1,151 @@
+package godotenv
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// MarshalOptions controls how Marshal/Write render key/value pairs.
+type MarshalOptions struct {
+	// ExportPrefix prepends "export " to every line so the output can be
+	// re-sourced directly by a shell.
+	ExportPrefix bool
+}
+
+// Unmarshal parses src and returns the resulting key/value map.
+func Unmarshal(src []byte) (map[string]string, error) {
+	out := make(map[string]string)
+	if err := parseBytes(src, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UnmarshalWithComments parses src and returns both the key/value map and
+// any trailing per-key comments collected along the way.
+func UnmarshalWithComments(src []byte) (values map[string]string, comments map[string]string, err error) {
+	values = make(map[string]string)
+	comments = make(map[string]string)
+	if err = parseBytesWithComments(src, values, comments); err != nil {
+		return nil, nil, err
+	}
+	return values, comments, nil
+}
+
+// Marshal renders values as an env file, one KEY=value line per entry in
+// key order.
+func Marshal(values map[string]string) ([]byte, error) {
+	return MarshalWithComments(values, nil)
+}
+
+// MarshalWithComments renders values as an env file, reattaching comments
+// as trailing "# ..." text on the line for the matching key.
+func MarshalWithComments(values, comments map[string]string) ([]byte, error) {
+	return MarshalWithOptions(values, comments, MarshalOptions{})
+}
+
+// MarshalWithOptions renders values as an env file under opts.
+func MarshalWithOptions(values, comments map[string]string, opts MarshalOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := Write(&buf, values, comments, opts); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Write renders values (and, when non-nil, their comments) to w under opts.
+func Write(w io.Writer, values, comments map[string]string, opts MarshalOptions) error {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if _, err := io.WriteString(w, formatLine(key, values[key], comments[key], opts)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// formatLine renders a single KEY=value line, quoting value when needed and
+// appending comment as a trailing "# ..." when present.
+func formatLine(key, value, comment string, opts MarshalOptions) string {
+	var b strings.Builder
+
+	if opts.ExportPrefix {
+		b.WriteString(exportPrefix)
+		b.WriteByte(' ')
+	}
+
+	b.WriteString(key)
+	b.WriteByte('=')
+	b.WriteString(quoteValue(value))
+
+	if comment != "" {
+		b.WriteString(" # ")
+		b.WriteString(comment)
+	}
+
+	b.WriteByte('\n')
+	return b.String()
+}
+
+// quoteValue renders value as it should appear on the right-hand side of a
+// KEY=value line, double-quoting and escaping it when it contains anything
+// extractQuotedValue/expandEscapes wouldn't otherwise round-trip.
+func quoteValue(value string) string {
+	if value == "" {
+		return `""`
+	}
+
+	if !needsQuoting(value) {
+		return value
+	}
+
+	var b strings.Builder
+	b.WriteByte(prefixDoubleQuote)
+
+	for _, r := range value {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '$':
+			// Every literal "$" is escaped here, even one that happened to
+			// be part of a "$(" sequence in the original value: expandEscapes
+			// leaves "\$" alone and expandVariables then strips just the
+			// backslash, so this still round-trips to a literal "$" rather
+			// than being read back as a command-substitution marker.
+			b.WriteString(`\$`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	b.WriteByte(prefixDoubleQuote)
+	return b.String()
+}
+
+// needsQuoting reports whether value must be double-quoted to round-trip
+// through the parser unchanged.
+func needsQuoting(value string) bool {
+	if strings.ContainsAny(value, "\n\r\"'#$") {
+		return true
+	}
+	// unquoted, a leading "<<" would be read back as a heredoc.
+	if strings.HasPrefix(value, heredocPrefix) {
+		return true
+	}
+	return strings.IndexFunc(value, unicode.IsSpace) != -1
+}
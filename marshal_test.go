@@ -0,0 +1,83 @@
+package godotenv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMarshalUnmarshalRoundTrip(t *testing.T) {
+	cases := []map[string]string{
+		{},
+		{"FOO": "bar"},
+		{"FOO": "bar baz", "EMPTY": ""},
+		{"QUOTE": `she said "hi"`},
+		{"DOLLAR": "price: $5", "SUBSHELL": "$(echo hi)"},
+		{"HASH": "a#b", "TRAILING_HASH": "a # b"},
+		{"NEWLINE": "line1\nline2", "CARRIAGE": "a\rb"},
+		{"BACKSLASH": `a\b\\c`},
+		{"SINGLE_QUOTE": "it's here"},
+		{"MIXED": "tab\tnewline\nquote\"dollar$end"},
+	}
+
+	for i, values := range cases {
+		b, err := Marshal(values)
+		if err != nil {
+			t.Fatalf("case %d: Marshal: %v", i, err)
+		}
+
+		out, err := Unmarshal(b)
+		if err != nil {
+			t.Fatalf("case %d: Unmarshal(%q): %v", i, b, err)
+		}
+
+		if !reflect.DeepEqual(out, values) {
+			t.Errorf("case %d: round-trip mismatch\n in: %#v\nout: %#v\nraw: %q", i, values, out, b)
+		}
+	}
+}
+
+func TestMarshalWithCommentsRoundTrip(t *testing.T) {
+	values := map[string]string{"PORT": "8080", "NAME": "svc"}
+	comments := map[string]string{"PORT": "Number", "NAME": "Plain"}
+
+	b, err := MarshalWithComments(values, comments)
+	if err != nil {
+		t.Fatalf("MarshalWithComments: %v", err)
+	}
+
+	outValues, outComments, err := UnmarshalWithComments(b)
+	if err != nil {
+		t.Fatalf("UnmarshalWithComments(%q): %v", b, err)
+	}
+
+	if !reflect.DeepEqual(outValues, values) {
+		t.Errorf("values mismatch: got %#v want %#v", outValues, values)
+	}
+	if !reflect.DeepEqual(outComments, comments) {
+		t.Errorf("comments mismatch: got %#v want %#v", outComments, comments)
+	}
+}
+
+func TestMarshalWithOptionsExportPrefix(t *testing.T) {
+	b, err := MarshalWithOptions(map[string]string{"FOO": "bar"}, nil, MarshalOptions{ExportPrefix: true})
+	if err != nil {
+		t.Fatalf("MarshalWithOptions: %v", err)
+	}
+
+	want := "export FOO=bar\n"
+	if string(b) != want {
+		t.Errorf("got %q, want %q", b, want)
+	}
+}
+
+func TestQuoteValueOnlyQuotesWhenNeeded(t *testing.T) {
+	if got := quoteValue("simple"); got != "simple" {
+		t.Errorf("quoteValue(simple) = %q, want unquoted", got)
+	}
+	if got := quoteValue(""); got != `""` {
+		t.Errorf(`quoteValue("") = %q, want ""`, got)
+	}
+	if got := quoteValue("has space"); got != `"has space"` {
+		t.Errorf("quoteValue(has space) = %q", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"regexp"
 	"strings"
 	"unicode"
@@ -21,39 +22,31 @@ func parseBytes(src []byte, out map[string]string) error {
 	return parseBytesWithComments(src, out, nil)
 }
 
+// parseBytesWithComments drives a Scanner over src, folding each statement
+// into out (and comments, when non-nil) as it is produced. It is the
+// non-streaming entry point kept for callers that want the whole file
+// parsed into a map in one call; Scanner/Decoder are available directly
+// for callers that want to consume statements incrementally.
 func parseBytesWithComments(src []byte, out map[string]string, comments map[string]string) error {
-	src = bytes.Replace(src, []byte("\r\n"), []byte("\n"), -1)
-	cutset := src
-	for {
-		cutset = getStatementStart(cutset)
-		if cutset == nil {
-			// reached end of file
-			break
-		}
-
-		key, left, err := locateKeyName(cutset)
-		if err != nil {
-			return err
-		}
+	sc := NewScanner(src)
+	for k, v := range out {
+		sc.vars[k] = v
+	}
 
-		if key == "" {
+	for {
+		stmt, err := sc.Next()
+		if err == io.EOF {
 			return nil
 		}
-
-		value, comment, left, err := extractVarValue(left, out)
 		if err != nil {
 			return err
 		}
 
-		out[key] = value
-		cutset = left
-
-		if comments != nil && len(comment) > 0 {
-			comments[key] = comment
+		out[stmt.Key] = stmt.Value
+		if comments != nil && stmt.Comment != "" {
+			comments[stmt.Key] = stmt.Comment
 		}
 	}
-
-	return nil
 }
 
 // getStatementPosition returns position of statement begin.
@@ -129,17 +122,21 @@ loop:
 }
 
 // extractVarValue extracts variable value and returns the rest of the slice
-func extractVarValue(src []byte, vars map[string]string) (value string, comment string, rest []byte, err error) {
+func extractVarValue(src []byte, vars map[string]string, opts ExpandOptions) (value string, comment string, rest []byte, err error) {
+	if bytes.HasPrefix(src, []byte(heredocPrefix)) {
+		return extractHeredocValue(src, vars, opts)
+	}
+
 	quote, hasPrefix := hasQuotePrefix(src)
 	if !hasPrefix {
-		return extractUnquotedValue(src, vars)
+		return extractUnquotedValue(src, vars, opts)
 	}
 
-	return extractQuotedValue(src, vars, quote)
+	return extractQuotedValue(src, vars, quote, opts)
 }
 
 // extractUnquotedValue extracts unquoted variable value and returns the rest of the slice
-func extractUnquotedValue(src []byte, vars map[string]string) (value string, comment string, rest []byte, err error) {
+func extractUnquotedValue(src []byte, vars map[string]string, opts ExpandOptions) (value string, comment string, rest []byte, err error) {
 	endOfLine := findEndOfLine(src)
 
 	if endOfLine == -1 {
@@ -163,44 +160,70 @@ func extractUnquotedValue(src []byte, vars map[string]string) (value string, com
 		}
 	}
 
-	return expandVariables(trimmed, vars), comment, src[endOfLine:], nil
+	value, err = expandVariables(trimmed, vars, opts)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	return value, comment, src[endOfLine:], nil
 }
 
-// extractQuotedValue extracts quoted variable value and returns the rest of the slice
-func extractQuotedValue(src []byte, vars map[string]string, quote byte) (value string, comment string, rest []byte, err error) {
+// extractQuotedValue extracts quoted variable value and returns the rest of
+// the slice. The search runs over the whole remaining source, not just the
+// current line, so single- and double-quoted values may span multiple
+// lines; only a double quote supports backslash-escaping, since single
+// quotes are fully literal in POSIX shells and never hide the closing quote.
+func extractQuotedValue(src []byte, vars map[string]string, quote byte, opts ExpandOptions) (value string, comment string, rest []byte, err error) {
 	for i := 1; i < len(src); i++ {
 		if char := src[i]; char != quote {
 			continue
 		}
 
-		if prevChar := src[i-1]; prevChar == '\\' {
-			continue
-		}
-
-		trimFunc := isCharFunc(rune(quote))
-		value = string(bytes.TrimLeftFunc(bytes.TrimRightFunc(src[0:i], trimFunc), trimFunc))
-		endOfLine := findEndOfLine(src)
-
-		if endOfLine == -1 {
-			endOfLine = len(src)
-
-			if endOfLine == 0 {
-				return "", "", nil, nil
+		if quote == prefixDoubleQuote {
+			// The quote is escaped only by an odd run of backslashes; in
+			// `"a\\"` the backslash is itself escaped and the quote closes.
+			escaped := false
+			for j := i - 1; j > 0 && src[j] == '\\'; j-- {
+				escaped = !escaped
+			}
+			if escaped {
+				continue
 			}
 		}
 
-		line := []rune(string(src[0:endOfLine]))
-		endOfVar := findEndOfVar(line)
-
-		if endOfLine > endOfVar+1 {
-			comment = strings.TrimSpace(string(src[endOfVar+1 : endOfLine]))
+		// src[0] is the opening quote (checked by the caller) and src[i] is
+		// this unescaped closing quote, so the literal content is exactly
+		// the bytes between them. Trimming quote runes off both ends here
+		// instead would also eat a legitimate escaped quote that happens to
+		// sit right before the real closing one (e.g. `"a\""` containing
+		// the value a").
+		value = string(src[1:i])
+
+		// Only look for a trailing comment in the text after the closing
+		// quote, so a "#" that happens to appear inside the quoted value
+		// itself is never mistaken for one.
+		afterQuote := src[i+1:]
+		endOfLine := findEndOfLine(afterQuote)
+		trailing := []rune(string(afterQuote[:endOfLine]))
+		endOfVar := findEndOfVar(trailing)
+
+		if len(trailing) > endOfVar+1 {
+			comment = strings.TrimSpace(string(trailing[endOfVar+1:]))
+		} else if extra := strings.TrimSpace(string(trailing)); extra != "" {
+			// Anything left after the closing quote that isn't a proper
+			// " # comment" is ambiguous input, not a silently-ignored
+			// trailer, e.g. `KEY="value"#trailing` or `KEY="value" junk`.
+			return "", "", nil, fmt.Errorf("unexpected characters %q after closing quote", extra)
 		}
 
 		if quote == prefixDoubleQuote {
-			value = expandVariables(expandEscapes(value), vars)
+			value, err = expandVariables(expandEscapes(value), vars, opts)
+			if err != nil {
+				return "", "", nil, err
+			}
 		}
 
-		return value, comment, src[i+1:], nil
+		return value, comment, afterQuote[endOfLine:], nil
 	}
 
 	valEndIndex := findEndOfLine(src)
@@ -211,6 +234,71 @@ func extractQuotedValue(src []byte, vars map[string]string, quote byte) (value s
 	return "", "", nil, fmt.Errorf("unterminated quoted value %s", src[:valEndIndex])
 }
 
+// heredocPrefix introduces a heredoc-style value: KEY=<<EOF ... EOF. The
+// delimiter may optionally be quoted (<<'EOF'/<<"EOF") to suppress $VAR
+// expansion within the body, mirroring shell heredoc semantics.
+const heredocPrefix = "<<"
+
+// extractHeredocValue extracts a heredoc value introduced by heredocPrefix
+// and returns the rest of the slice after the closing delimiter line.
+func extractHeredocValue(src []byte, vars map[string]string, opts ExpandOptions) (value string, comment string, rest []byte, err error) {
+	body := src[len(heredocPrefix):]
+
+	quote := byte(0)
+	if q, ok := hasQuotePrefix(body); ok {
+		quote = q
+		body = body[1:]
+	}
+
+	delimEnd := findEndOfLine(body)
+	delimLine := body[:delimEnd]
+	if quote != 0 {
+		delimLine = bytes.TrimSuffix(delimLine, []byte{quote})
+	}
+
+	delim := bytes.TrimSpace(delimLine)
+	if len(delim) == 0 {
+		return "", "", nil, fmt.Errorf("heredoc missing delimiter near %q", body[:delimEnd])
+	}
+
+	body = body[delimEnd:]
+	if len(body) > 0 && body[0] == '\n' {
+		body = body[1:]
+	}
+
+	for start := 0; ; {
+		nl := bytes.IndexByte(body[start:], '\n')
+		lineEnd := len(body)
+		if nl != -1 {
+			lineEnd = start + nl
+		}
+
+		if bytes.Equal(bytes.TrimRight(body[start:lineEnd], "\r"), delim) {
+			value = string(bytes.TrimSuffix(body[:start], []byte("\n")))
+
+			rest = body[lineEnd:]
+			if len(rest) > 0 && rest[0] == '\n' {
+				rest = rest[1:]
+			}
+
+			if quote == 0 {
+				value, err = expandVariables(value, vars, opts)
+				if err != nil {
+					return "", "", nil, err
+				}
+			}
+
+			return value, "", rest, nil
+		}
+
+		if nl == -1 {
+			return "", "", nil, fmt.Errorf("unterminated heredoc %q", delim)
+		}
+
+		start = lineEnd + 1
+	}
+}
+
 // findEndOfLine finds the index of the end of the line
 func findEndOfLine(src []byte) int {
 	endOfLine := bytes.IndexFunc(src, isLineEnd)
@@ -295,22 +383,5 @@ func isLineEnd(r rune) bool {
 
 var (
 	escapeRegex        = regexp.MustCompile(`\\.`)
-	expandVarRegex     = regexp.MustCompile(`(\\)?(\$)(\()?\{?([A-Z0-9_]+)?\}?`)
 	unescapeCharsRegex = regexp.MustCompile(`\\([^$])`)
 )
-
-func expandVariables(v string, m map[string]string) string {
-	return expandVarRegex.ReplaceAllStringFunc(v, func(s string) string {
-		submatch := expandVarRegex.FindStringSubmatch(s)
-
-		if submatch == nil {
-			return s
-		}
-		if submatch[1] == "\\" || submatch[2] == "(" {
-			return submatch[0][1:]
-		} else if submatch[4] != "" {
-			return m[submatch[4]]
-		}
-		return s
-	})
-}
@@ -0,0 +1,104 @@
+package godotenv
+
+import "testing"
+
+func TestParseHeredocPlain(t *testing.T) {
+	src := "KEY=<<EOF\nline1\nline2\nEOF\nNEXT=after\n"
+
+	out := make(map[string]string)
+	if err := parseBytes([]byte(src), out); err != nil {
+		t.Fatalf("parseBytes: %v", err)
+	}
+
+	if out["KEY"] != "line1\nline2" {
+		t.Errorf("KEY = %q, want %q", out["KEY"], "line1\nline2")
+	}
+	if out["NEXT"] != "after" {
+		t.Errorf("NEXT = %q, want after", out["NEXT"])
+	}
+}
+
+func TestParseHeredocQuotedDelimiterSuppressesExpansion(t *testing.T) {
+	src := "FOO=bar\nKEY=<<'EOF'\n$FOO literal\nEOF\n"
+
+	out := make(map[string]string)
+	if err := parseBytes([]byte(src), out); err != nil {
+		t.Fatalf("parseBytes: %v", err)
+	}
+
+	if out["KEY"] != "$FOO literal" {
+		t.Errorf("KEY = %q, want literal $FOO unexpanded", out["KEY"])
+	}
+}
+
+func TestParseHeredocUnquotedDelimiterExpands(t *testing.T) {
+	src := "FOO=bar\nKEY=<<EOF\n$FOO expanded\nEOF\n"
+
+	out := make(map[string]string)
+	if err := parseBytes([]byte(src), out); err != nil {
+		t.Fatalf("parseBytes: %v", err)
+	}
+
+	if out["KEY"] != "bar expanded" {
+		t.Errorf("KEY = %q, want bar expanded", out["KEY"])
+	}
+}
+
+func TestParseMultilineDoubleQuotedValue(t *testing.T) {
+	src := "KEY=\"line1\nline2\"\n"
+
+	out := make(map[string]string)
+	if err := parseBytes([]byte(src), out); err != nil {
+		t.Fatalf("parseBytes: %v", err)
+	}
+
+	if out["KEY"] != "line1\nline2" {
+		t.Errorf("KEY = %q, want %q", out["KEY"], "line1\nline2")
+	}
+}
+
+func TestParseSingleQuoteLiteralBackslash(t *testing.T) {
+	src := `KEY='a\nb'` + "\n"
+
+	out := make(map[string]string)
+	if err := parseBytes([]byte(src), out); err != nil {
+		t.Fatalf("parseBytes: %v", err)
+	}
+
+	if out["KEY"] != `a\nb` {
+		t.Errorf("KEY = %q, want literal a\\nb", out["KEY"])
+	}
+}
+
+func TestParseTrailingTextAfterQuoteErrors(t *testing.T) {
+	src := `KEY="value"#trailing` + "\n"
+
+	out := make(map[string]string)
+	if err := parseBytes([]byte(src), out); err == nil {
+		t.Fatal("expected an error for trailing text after the closing quote")
+	}
+}
+
+func TestParseCommentBoundary(t *testing.T) {
+	values, comments, err := UnmarshalWithComments([]byte("FOO=bar # a comment\n"))
+	if err != nil {
+		t.Fatalf("UnmarshalWithComments: %v", err)
+	}
+	if values["FOO"] != "bar" {
+		t.Errorf("FOO = %q, want bar", values["FOO"])
+	}
+	if comments["FOO"] != "a comment" {
+		t.Errorf("comment = %q, want %q", comments["FOO"], "a comment")
+	}
+
+	values, comments, err = UnmarshalWithComments([]byte("BAR=foo#baz\n"))
+	if err != nil {
+		t.Fatalf("UnmarshalWithComments: %v", err)
+	}
+	if values["BAR"] != "foo#baz" {
+		t.Errorf("BAR = %q, want the whole unquoted token kept as the value", values["BAR"])
+	}
+	if comments["BAR"] != "" {
+		t.Errorf("comment = %q, want none ('#' not preceded by a space isn't a comment)", comments["BAR"])
+	}
+}
@@ -0,0 +1,182 @@
+package godotenv
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Statement is a single key/value assignment parsed from an env source,
+// together with its position in the original input.
+type Statement struct {
+	Key     string
+	Value   string
+	Comment string
+	Line    int
+	Col     int
+	Raw     []byte
+}
+
+// ParseError reports a parse failure together with the line and column at
+// which it occurred, and renders a caret-style excerpt of the offending line.
+type ParseError struct {
+	Line int
+	Col  int
+	Err  error
+
+	line []byte
+}
+
+func (e *ParseError) Error() string {
+	col := e.Col
+	if col < 1 {
+		col = 1
+	}
+
+	return fmt.Sprintf("%s at line %d, col %d\n\t%s\n\t%s^",
+		e.Err, e.Line, col, e.line, strings.Repeat(" ", col-1))
+}
+
+// Unwrap allows errors.Is/errors.As to reach the underlying parse error.
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// Scanner reads Statements from an in-memory env source one at a time,
+// tracking line and column offsets as it goes. Unlike parseBytesWithComments
+// it fills no output map for the caller, so callers can consume large files
+// incrementally; it does keep each value parsed so far, so that later
+// statements can expand references to earlier keys.
+type Scanner struct {
+	src       []byte
+	pos       int
+	line      int
+	lineStart int
+	vars      map[string]string
+	opts      ExpandOptions
+}
+
+// NewScanner returns a Scanner over src.
+func NewScanner(src []byte) *Scanner {
+	return &Scanner{
+		src:  bytes.Replace(src, []byte("\r\n"), []byte("\n"), -1),
+		line: 1,
+		vars: make(map[string]string),
+	}
+}
+
+// NewScannerWithOptions returns a Scanner over src that expands variable
+// references under opts, e.g. falling back to os.LookupEnv for names not
+// yet defined locally.
+func NewScannerWithOptions(src []byte, opts ExpandOptions) *Scanner {
+	s := NewScanner(src)
+	s.opts = opts
+	return s
+}
+
+// Decoder reads Statements from an io.Reader. It buffers the underlying
+// source once on construction, then drives a Scanner over it, so that
+// Next can be called repeatedly without the caller holding the whole
+// file's worth of parsed output at once.
+type Decoder struct {
+	scanner *Scanner
+}
+
+// NewDecoder returns a Decoder that reads env statements from r.
+func NewDecoder(r io.Reader) (*Decoder, error) {
+	return NewDecoderWithOptions(r, ExpandOptions{})
+}
+
+// NewDecoderWithOptions returns a Decoder that reads env statements from r,
+// expanding variable references under opts.
+func NewDecoderWithOptions(r io.Reader, opts ExpandOptions) (*Decoder, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Decoder{scanner: NewScannerWithOptions(src, opts)}, nil
+}
+
+// Next returns the next statement, or io.EOF once the source is exhausted.
+func (d *Decoder) Next() (Statement, error) {
+	return d.scanner.Next()
+}
+
+// Next scans and returns the next statement, or io.EOF once the source is
+// exhausted. A statement with an empty key (a line such as "=value") also
+// ends the scan with io.EOF.
+func (s *Scanner) Next() (Statement, error) {
+	rest := s.src[s.pos:]
+	stmtStart := getStatementStart(rest)
+	if stmtStart == nil {
+		s.advanceTo(len(s.src))
+		return Statement{}, io.EOF
+	}
+	s.advanceTo(len(s.src) - len(stmtStart))
+
+	line, col := s.line, s.col()
+
+	key, left, err := locateKeyName(stmtStart)
+	if err != nil {
+		return Statement{}, s.wrapErr(err, line, col)
+	}
+
+	if key == "" {
+		s.advanceTo(len(s.src))
+		return Statement{}, io.EOF
+	}
+	s.advanceTo(len(s.src) - len(left))
+
+	value, comment, tail, err := extractVarValue(left, s.vars, s.opts)
+	if err != nil {
+		return Statement{}, s.wrapErr(err, line, col)
+	}
+
+	rawStart := len(s.src) - len(stmtStart)
+	rawEnd := len(s.src) - len(tail)
+	stmt := Statement{
+		Key:     key,
+		Value:   value,
+		Comment: comment,
+		Line:    line,
+		Col:     col,
+		Raw:     s.src[rawStart:rawEnd],
+	}
+
+	s.vars[key] = value
+	s.advanceTo(rawEnd)
+
+	return stmt, nil
+}
+
+// advanceTo moves the scanner's cursor to newPos, updating line/column
+// bookkeeping for everything skipped over.
+func (s *Scanner) advanceTo(newPos int) {
+	for i := s.pos; i < newPos; i++ {
+		if s.src[i] == '\n' {
+			s.line++
+			s.lineStart = i + 1
+		}
+	}
+	s.pos = newPos
+}
+
+// col returns the current 1-based column within the current line.
+func (s *Scanner) col() int {
+	return s.pos - s.lineStart + 1
+}
+
+// wrapErr attaches position information and the offending source line to err.
+func (s *Scanner) wrapErr(err error, line, col int) error {
+	end := bytes.IndexByte(s.src[s.lineStart:], '\n')
+	if end == -1 {
+		end = len(s.src) - s.lineStart
+	}
+
+	return &ParseError{
+		Line: line,
+		Col:  col,
+		Err:  err,
+		line: s.src[s.lineStart : s.lineStart+end],
+	}
+}
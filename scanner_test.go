@@ -0,0 +1,153 @@
+package godotenv
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestScannerNextPositionsAndValues(t *testing.T) {
+	src := "FOO=bar\nBAR=${FOO}baz\n"
+
+	sc := NewScanner([]byte(src))
+
+	stmt, err := sc.Next()
+	if err != nil {
+		t.Fatalf("first Next: %v", err)
+	}
+	if stmt.Key != "FOO" || stmt.Value != "bar" || stmt.Line != 1 || stmt.Col != 1 {
+		t.Fatalf("unexpected first statement: %+v", stmt)
+	}
+
+	stmt, err = sc.Next()
+	if err != nil {
+		t.Fatalf("second Next: %v", err)
+	}
+	if stmt.Key != "BAR" || stmt.Value != "barbaz" || stmt.Line != 2 || stmt.Col != 1 {
+		t.Fatalf("unexpected second statement: %+v", stmt)
+	}
+
+	if _, err := sc.Next(); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestScannerErrorIncludesPosition(t *testing.T) {
+	src := "FOO=bar\nBAD;KEY=oops\n"
+
+	sc := NewScanner([]byte(src))
+
+	if _, err := sc.Next(); err != nil {
+		t.Fatalf("first Next: %v", err)
+	}
+
+	_, err := sc.Next()
+	if err == nil {
+		t.Fatal("expected an error on the malformed second line")
+	}
+
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected *ParseError, got %T: %v", err, err)
+	}
+	if perr.Line != 2 {
+		t.Errorf("Line = %d, want 2", perr.Line)
+	}
+	if !strings.Contains(err.Error(), "BAD;KEY=oops") {
+		t.Errorf("Error() = %q, want it to include the offending line", err.Error())
+	}
+}
+
+func TestStatementRawCapturesSourceText(t *testing.T) {
+	src := "FOO=bar\nBAR=\"baz qux\" # a comment\n"
+
+	sc := NewScanner([]byte(src))
+
+	stmt, err := sc.Next()
+	if err != nil {
+		t.Fatalf("first Next: %v", err)
+	}
+	if string(stmt.Raw) != "FOO=bar" {
+		t.Errorf("first Raw = %q, want %q", stmt.Raw, "FOO=bar")
+	}
+
+	stmt, err = sc.Next()
+	if err != nil {
+		t.Fatalf("second Next: %v", err)
+	}
+	if string(stmt.Raw) != `BAR="baz qux" # a comment` {
+		t.Errorf("second Raw = %q, want %q", stmt.Raw, `BAR="baz qux" # a comment`)
+	}
+}
+
+func TestScannerWithOptionsLookupFallback(t *testing.T) {
+	opts := ExpandOptions{
+		Lookup: func(name string) (string, bool) {
+			if name == "HOME" {
+				return "/home/test", true
+			}
+			return "", false
+		},
+	}
+
+	sc := NewScannerWithOptions([]byte("PATH=$HOME/bin\n"), opts)
+
+	stmt, err := sc.Next()
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if stmt.Value != "/home/test/bin" {
+		t.Errorf("Value = %q, want /home/test/bin", stmt.Value)
+	}
+}
+
+func TestDecoderWithOptionsLookupFallback(t *testing.T) {
+	opts := ExpandOptions{
+		Lookup: func(name string) (string, bool) {
+			if name == "HOME" {
+				return "/home/test", true
+			}
+			return "", false
+		},
+	}
+
+	dec, err := NewDecoderWithOptions(strings.NewReader("PATH=$HOME/bin\n"), opts)
+	if err != nil {
+		t.Fatalf("NewDecoderWithOptions: %v", err)
+	}
+
+	stmt, err := dec.Next()
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if stmt.Value != "/home/test/bin" {
+		t.Errorf("Value = %q, want /home/test/bin", stmt.Value)
+	}
+}
+
+func TestDecoderNext(t *testing.T) {
+	r := strings.NewReader("A=1\nB=2\n")
+
+	dec, err := NewDecoder(r)
+	if err != nil {
+		t.Fatalf("NewDecoder: %v", err)
+	}
+
+	var keys []string
+	for {
+		stmt, err := dec.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		keys = append(keys, stmt.Key+"="+stmt.Value)
+	}
+
+	want := []string{"A=1", "B=2"}
+	if len(keys) != len(want) || keys[0] != want[0] || keys[1] != want[1] {
+		t.Errorf("got %v, want %v", keys, want)
+	}
+}
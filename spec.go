@@ -2,6 +2,7 @@ package godotenv
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -17,6 +18,12 @@ const (
 	SpecNameSecret            = "Secret"   // SpecNameSecret specifies a secret specification.
 	SpecNamePassword          = "Password" // SpecNamePassword specifies a password specification.
 	SpecNameNumber            = "Number"   // SpecNameNumber specifies a number specification.
+	SpecNameEnum              = "Enum"     // SpecNameEnum specifies a one-of-a-set specification.
+	SpecNameRegex             = "Regex"    // SpecNameRegex specifies a pattern-matched specification.
+	SpecNameURL               = "URL"      // SpecNameURL specifies a URL specification.
+	SpecNameEmail             = "Email"    // SpecNameEmail specifies an email address specification.
+	SpecNameDuration          = "Duration" // SpecNameDuration specifies a time.Duration specification.
+	SpecNameBase64            = "Base64"   // SpecNameBase64 specifies a base64-encoded specification.
 )
 
 // Spec represents the available configuration options and their flags.
@@ -24,36 +31,69 @@ type Spec struct {
 	Name     SpecName
 	Required bool // Indicates whether the configuration is required.
 	Valid    bool // Indicates whether the configuration is valid.
+	Error    string
+	Params   map[string]interface{}
 }
 
 // Specs represents a collection of configuration specifications.
 type Specs map[string]Spec
 
-// Define the mapping between flags and their corresponding specifications.
+// specsHandlers is the package-level registry of spec validators. It is
+// exported for mutation only through RegisterSpecHandler; the map itself
+// stays unexported so every write goes through that function.
 var specsHandlers = map[SpecName]func(*Spec, string, map[string]interface{}){
 	SpecNameOpaque:   validateInput,
 	SpecNamePlain:    validateInput,
 	SpecNameSecret:   validateInput,
 	SpecNamePassword: validateInput,
-	SpecNameNumber:   validateInput,
+	SpecNameNumber:   validateNumber,
+	SpecNameEnum:     validateEnum,
+	SpecNameRegex:    validateRegex,
+	SpecNameURL:      validateURL,
+	SpecNameEmail:    validateEmail,
+	SpecNameDuration: validateDuration,
+	SpecNameBase64:   validateBase64,
 }
 
-// Handler function to validate various types of input
+// RegisterSpecHandler registers fn as the validator used for specs named
+// name, overriding any existing handler (built-in or previously registered)
+// for that name. It is not safe to call concurrently with
+// GenerateSpecsFromComments/GenerateSpecsFromCommentsE.
+func RegisterSpecHandler(name SpecName, fn func(*Spec, string, map[string]interface{})) {
+	specsHandlers[name] = fn
+}
+
+// Handler function to validate various types of input. A "length" param, if
+// present, is enforced as a minimum length regardless of Required, so that
+// it matches the minLength a Specs.JSONSchema derives from it.
 func validateInput(spec *Spec, value string, params map[string]interface{}) {
-	if strings.TrimSpace(value) != "" {
-		spec.Valid = true
-		if spec.Required && params != nil {
-			if length, ok := params["length"].(float64); ok {
-				spec.Valid = len(value) == int(length)
-			}
-		}
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+
+	spec.Valid = true
+
+	if length, ok := params["length"].(float64); ok && len(value) < int(length) {
+		spec.Valid = false
+		spec.Error = fmt.Sprintf("must be at least %d characters", int(length))
 	}
 }
 
 // GenerateSpecsFromComments maps comments to configuration key specifications.
+// Malformed spec params are ignored; use GenerateSpecsFromCommentsE if you
+// need to know when that happens.
 func GenerateSpecsFromComments(values map[string]string, comments map[string]string) Specs {
+	specs, _ := GenerateSpecsFromCommentsE(values, comments)
+	return specs
+}
+
+// GenerateSpecsFromCommentsE maps comments to configuration key
+// specifications, same as GenerateSpecsFromComments, but also returns an
+// error describing any comment whose params could not be parsed as JSON.
+func GenerateSpecsFromCommentsE(values map[string]string, comments map[string]string) (Specs, error) {
 	// Initialize a new Specs map to store configuration specifications.
 	specs := make(Specs)
+	var paramErrs []string
 
 	// Iterate through each key-value pair in the comments map.
 	for key, value := range values {
@@ -69,17 +109,15 @@ func GenerateSpecsFromComments(values map[string]string, comments map[string]str
 
 		// Split the comment into name and parameter.
 		parts := strings.SplitN(comment, ":", 2)
-		name := upperFirstLetter(parts[0])
-		var params string
+		name := parts[0]
 		var jsonMap map[string]interface{}
 
 		if len(parts) > 1 {
-			params = parts[1]
-			bytes := []byte(params)
 			jsonMap = make(map[string]interface{})
 
-			if err := json.Unmarshal(bytes, &jsonMap); err != nil {
-				fmt.Printf("Wrong params format for %s\n", key)
+			if err := json.Unmarshal([]byte(parts[1]), &jsonMap); err != nil {
+				paramErrs = append(paramErrs, fmt.Sprintf("wrong params format for %s: %s", key, err))
+				jsonMap = nil
 			}
 		}
 
@@ -90,9 +128,11 @@ func GenerateSpecsFromComments(values map[string]string, comments map[string]str
 
 		name = strings.TrimSuffix(name, "!")
 		if name != "" {
-			spec.Name = SpecName(name)
+			spec.Name = normalizeSpecName(name)
 		}
 
+		spec.Params = jsonMap
+
 		// Check if the name is recognized and apply its parameters.
 		if handler, ok := specsHandlers[spec.Name]; ok {
 			handler(&spec, value, jsonMap)
@@ -102,8 +142,12 @@ func GenerateSpecsFromComments(values map[string]string, comments map[string]str
 		specs[key] = spec
 	}
 
+	if len(paramErrs) > 0 {
+		return specs, errors.New(strings.Join(paramErrs, "; "))
+	}
+
 	// Return the populated Specs map.
-	return specs
+	return specs, nil
 }
 
 func upperFirstLetter(s string) string {
@@ -115,3 +159,18 @@ func upperFirstLetter(s string) string {
 	// Concatenate it with the rest of the string
 	return strings.ToUpper(string(s[0])) + strings.ToLower((s[1:]))
 }
+
+// normalizeSpecName canonicalizes a comment-supplied spec name against the
+// registered handlers, matching case-insensitively so abbreviations like
+// SpecNameURL ("URL") round-trip regardless of how the comment cased them
+// (upperFirstLetter alone would turn "URL" into "Url", which matches no
+// registered handler). Names that don't match any registered handler fall
+// back to upperFirstLetter, preserving prior behavior for custom specs.
+func normalizeSpecName(raw string) SpecName {
+	for registered := range specsHandlers {
+		if strings.EqualFold(string(registered), raw) {
+			return registered
+		}
+	}
+	return SpecName(upperFirstLetter(raw))
+}
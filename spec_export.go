@@ -0,0 +1,126 @@
+package godotenv
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// jsonSchemaProperty is a single property entry in a Draft-07 JSON Schema
+// document, covering the subset of keywords a Spec maps onto.
+type jsonSchemaProperty struct {
+	Type      string   `json:"type,omitempty"`
+	Pattern   string   `json:"pattern,omitempty"`
+	Enum      []string `json:"enum,omitempty"`
+	Format    string   `json:"format,omitempty"`
+	MinLength *int     `json:"minLength,omitempty"`
+	Minimum   *float64 `json:"minimum,omitempty"`
+	Maximum   *float64 `json:"maximum,omitempty"`
+}
+
+// jsonSchemaDoc is the Draft-07 document JSONSchema renders.
+type jsonSchemaDoc struct {
+	Schema     string                        `json:"$schema"`
+	Type       string                        `json:"type"`
+	Properties map[string]jsonSchemaProperty `json:"properties"`
+	Required   []string                      `json:"required,omitempty"`
+}
+
+// JSONSchema renders s as a Draft-07 JSON Schema document describing each
+// key's type and constraints (derived from its SpecName and Params),
+// suitable for editor autocompletion.
+func (s Specs) JSONSchema() ([]byte, error) {
+	doc := jsonSchemaDoc{
+		Schema:     "http://json-schema.org/draft-07/schema#",
+		Type:       "object",
+		Properties: make(map[string]jsonSchemaProperty, len(s)),
+	}
+
+	for key, spec := range s {
+		doc.Properties[key] = specJSONSchemaProperty(spec)
+		if spec.Required {
+			doc.Required = append(doc.Required, key)
+		}
+	}
+
+	sort.Strings(doc.Required)
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// specJSONSchemaProperty derives a JSON Schema property from a single Spec.
+func specJSONSchemaProperty(spec Spec) jsonSchemaProperty {
+	prop := jsonSchemaProperty{Type: "string"}
+
+	switch spec.Name {
+	case SpecNameNumber:
+		prop.Type = "number"
+		if wantInt, ok := spec.Params["int"].(bool); ok && wantInt {
+			prop.Type = "integer"
+		}
+		if min, ok := spec.Params["min"].(float64); ok {
+			prop.Minimum = &min
+		}
+		if max, ok := spec.Params["max"].(float64); ok {
+			prop.Maximum = &max
+		}
+	case SpecNameEnum:
+		if values, ok := spec.Params["values"].([]interface{}); ok {
+			for _, v := range values {
+				if sv, ok := v.(string); ok {
+					prop.Enum = append(prop.Enum, sv)
+				}
+			}
+		}
+	case SpecNameRegex:
+		if pattern, ok := spec.Params["pattern"].(string); ok {
+			prop.Pattern = pattern
+		}
+	case SpecNameURL:
+		prop.Format = "uri"
+	case SpecNameEmail:
+		prop.Format = "email"
+	case SpecNameDuration:
+		prop.Format = "duration"
+	case SpecNameBase64:
+		prop.Format = "byte"
+	}
+
+	if length, ok := spec.Params["length"].(float64); ok {
+		l := int(length)
+		prop.MinLength = &l
+	}
+
+	return prop
+}
+
+// Example renders values (and, when non-nil, comments) back out as
+// .env.example content, comments retained, redacting the value of any key
+// whose spec is SpecNameSecret or SpecNamePassword to "***".
+func (s Specs) Example(values map[string]string, comments map[string]string) []byte {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	for _, key := range keys {
+		rendered := quoteValue(values[key])
+		if spec, ok := s[key]; ok && (spec.Name == SpecNameSecret || spec.Name == SpecNamePassword) {
+			rendered = `"***"`
+		}
+
+		buf.WriteString(key)
+		buf.WriteByte('=')
+		buf.WriteString(rendered)
+
+		if comment := comments[key]; comment != "" {
+			buf.WriteString(" # ")
+			buf.WriteString(comment)
+		}
+
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes()
+}
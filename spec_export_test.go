@@ -0,0 +1,133 @@
+package godotenv
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSpecsJSONSchema(t *testing.T) {
+	values := map[string]string{
+		"PORT":  "8080",
+		"NAME":  "bob",
+		"LEVEL": "info",
+	}
+	comments := map[string]string{
+		"PORT":  `Number!:{"min":1,"max":65535}`,
+		"NAME":  `Plain:{"length":3}`,
+		"LEVEL": `Enum:{"values":["debug","info","warn"]}`,
+	}
+
+	specs := GenerateSpecsFromComments(values, comments)
+
+	b, err := specs.JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema: %v", err)
+	}
+
+	var doc struct {
+		Required   []string `json:"required"`
+		Properties map[string]struct {
+			Type      string   `json:"type"`
+			MinLength *int     `json:"minLength"`
+			Minimum   *float64 `json:"minimum"`
+			Maximum   *float64 `json:"maximum"`
+			Enum      []string `json:"enum"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatalf("unmarshal schema: %v", err)
+	}
+
+	if len(doc.Required) != 1 || doc.Required[0] != "PORT" {
+		t.Errorf("Required = %v, want [PORT]", doc.Required)
+	}
+
+	port := doc.Properties["PORT"]
+	if port.Type != "number" {
+		t.Errorf("PORT type = %q, want number", port.Type)
+	}
+	if port.Minimum == nil || *port.Minimum != 1 {
+		t.Errorf("PORT minimum = %v, want 1", port.Minimum)
+	}
+	if port.Maximum == nil || *port.Maximum != 65535 {
+		t.Errorf("PORT maximum = %v, want 65535", port.Maximum)
+	}
+
+	name := doc.Properties["NAME"]
+	if name.MinLength == nil || *name.MinLength != 3 {
+		t.Errorf("NAME minLength = %v, want 3", name.MinLength)
+	}
+
+	level := doc.Properties["LEVEL"]
+	want := []string{"debug", "info", "warn"}
+	if len(level.Enum) != len(want) {
+		t.Fatalf("LEVEL enum = %v, want %v", level.Enum, want)
+	}
+}
+
+func TestSpecsJSONSchemaMinLengthMatchesValidator(t *testing.T) {
+	// A non-required Plain spec with a "length" param now both validates and
+	// schema-exports as a real minimum, so the two stay in sync: Valid
+	// reflects whether the value actually met it, and MinLength mirrors the
+	// same threshold.
+	values := map[string]string{"NAME": "bo"}
+	comments := map[string]string{"NAME": `Plain:{"length":3}`}
+
+	specs := GenerateSpecsFromComments(values, comments)
+	if specs["NAME"].Valid {
+		t.Fatal("expected NAME to fail the minimum-length check")
+	}
+
+	b, err := specs.JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema: %v", err)
+	}
+
+	var doc struct {
+		Properties map[string]struct {
+			MinLength *int `json:"minLength"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatalf("unmarshal schema: %v", err)
+	}
+
+	if got := doc.Properties["NAME"].MinLength; got == nil || *got != 3 {
+		t.Errorf("MinLength = %v, want 3, matching the validator's real minimum", got)
+	}
+}
+
+func TestSpecsExampleRedactsSecretsAndPasswords(t *testing.T) {
+	values := map[string]string{
+		"TOKEN": "supersecret",
+		"NAME":  "bob",
+	}
+	comments := map[string]string{
+		"TOKEN": "Secret",
+		"NAME":  "Plain",
+	}
+
+	specs := GenerateSpecsFromComments(values, comments)
+	out := string(specs.Example(values, comments))
+
+	if !strings.Contains(out, `TOKEN="***"`) {
+		t.Errorf("expected TOKEN to be redacted, got %q", out)
+	}
+	if strings.Contains(out, "supersecret") {
+		t.Error("secret value leaked into Example output")
+	}
+	if !strings.Contains(out, "NAME=bob") {
+		t.Errorf("expected NAME to be left as-is, got %q", out)
+	}
+}
+
+func TestSpecsExampleKeepsComments(t *testing.T) {
+	values := map[string]string{"PORT": "8080"}
+	comments := map[string]string{"PORT": "Number"}
+
+	out := string(Specs{}.Example(values, comments))
+	if !strings.Contains(out, "PORT=8080 # Number") {
+		t.Errorf("got %q, want comment preserved", out)
+	}
+}
@@ -0,0 +1,64 @@
+package godotenv
+
+import "testing"
+
+// TestGenerateSpecsFromCommentsAllCapsName is a regression test: comment
+// names that are already in the registry's exact casing (e.g. "URL", not
+// title-cased "Url") must still resolve to that handler.
+func TestGenerateSpecsFromCommentsAllCapsName(t *testing.T) {
+	values := map[string]string{"SITE": "https://example.com"}
+	comments := map[string]string{"SITE": "URL"}
+
+	specs := GenerateSpecsFromComments(values, comments)
+
+	spec := specs["SITE"]
+	if spec.Name != SpecNameURL {
+		t.Fatalf("spec.Name = %q, want %q", spec.Name, SpecNameURL)
+	}
+	if !spec.Valid {
+		t.Errorf("expected a valid URL to pass validation, got error %q", spec.Error)
+	}
+}
+
+func TestGenerateSpecsFromCommentsNameIsCaseInsensitive(t *testing.T) {
+	for _, name := range []string{"url", "Url", "URL", "uRl"} {
+		specs := GenerateSpecsFromComments(
+			map[string]string{"SITE": "https://example.com"},
+			map[string]string{"SITE": name},
+		)
+		if specs["SITE"].Name != SpecNameURL {
+			t.Errorf("comment %q: Name = %q, want %q", name, specs["SITE"].Name, SpecNameURL)
+		}
+	}
+}
+
+// TestDecodeURLSpecAcceptsValidURL reproduces the reported bug end to end:
+// a field tagged with the "URL" spec must accept a valid URL through Decode,
+// not fail validation because the comment-name normalization mangled "URL"
+// into something the validator registry doesn't recognize.
+func TestDecodeURLSpecAcceptsValidURL(t *testing.T) {
+	type cfg struct {
+		Site string `env:"SITE"`
+	}
+
+	var c cfg
+	err := Decode(&c, map[string]string{"SITE": "https://example.com"}, map[string]string{"SITE": "URL"})
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if c.Site != "https://example.com" {
+		t.Errorf("Site = %q, want https://example.com", c.Site)
+	}
+}
+
+func TestDecodeURLSpecRejectsInvalidURL(t *testing.T) {
+	type cfg struct {
+		Site string `env:"SITE"`
+	}
+
+	var c cfg
+	err := Decode(&c, map[string]string{"SITE": "not a url"}, map[string]string{"SITE": "URL"})
+	if err == nil {
+		t.Fatal("expected an error for an invalid URL")
+	}
+}
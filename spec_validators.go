@@ -0,0 +1,150 @@
+package godotenv
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// validateNumber implements SpecNameNumber: value must parse as a float64,
+// optionally bounded by "min"/"max" params and restricted to integers by an
+// "int": true param.
+func validateNumber(spec *Spec, value string, params map[string]interface{}) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return
+	}
+
+	f, err := strconv.ParseFloat(trimmed, 64)
+	if err != nil {
+		spec.Error = fmt.Sprintf("%q is not a number", value)
+		return
+	}
+
+	if min, ok := numberParam(params, "min"); ok && f < min {
+		spec.Error = fmt.Sprintf("%v is less than minimum %v", f, min)
+		return
+	}
+
+	if max, ok := numberParam(params, "max"); ok && f > max {
+		spec.Error = fmt.Sprintf("%v is greater than maximum %v", f, max)
+		return
+	}
+
+	if wantInt, ok := params["int"].(bool); ok && wantInt && f != math.Trunc(f) {
+		spec.Error = fmt.Sprintf("%v is not an integer", f)
+		return
+	}
+
+	spec.Valid = true
+}
+
+// numberParam reads a JSON-decoded numeric param, which always arrives as a
+// float64 via encoding/json.
+func numberParam(params map[string]interface{}, key string) (float64, bool) {
+	v, ok := params[key].(float64)
+	return v, ok
+}
+
+// validateEnum implements SpecNameEnum: value must equal one of the strings
+// in the "values" param.
+func validateEnum(spec *Spec, value string, params map[string]interface{}) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+
+	values, _ := params["values"].([]interface{})
+	for _, v := range values {
+		if s, ok := v.(string); ok && s == value {
+			spec.Valid = true
+			return
+		}
+	}
+
+	spec.Error = fmt.Sprintf("%q is not one of %v", value, values)
+}
+
+// validateRegex implements SpecNameRegex: value must match the "pattern" param.
+func validateRegex(spec *Spec, value string, params map[string]interface{}) {
+	pattern, _ := params["pattern"].(string)
+	if pattern == "" {
+		spec.Error = `Regex spec requires a "pattern" param`
+		return
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		spec.Error = fmt.Sprintf("invalid pattern %q: %s", pattern, err)
+		return
+	}
+
+	if !re.MatchString(value) {
+		spec.Error = fmt.Sprintf("%q does not match pattern %q", value, pattern)
+		return
+	}
+
+	spec.Valid = true
+}
+
+// validateURL implements SpecNameURL: value must parse as an absolute URL.
+func validateURL(spec *Spec, value string, params map[string]interface{}) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+
+	u, err := url.ParseRequestURI(value)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		spec.Error = fmt.Sprintf("%q is not a valid URL", value)
+		return
+	}
+
+	spec.Valid = true
+}
+
+// validateEmail implements SpecNameEmail: value must parse as an RFC 5322 address.
+func validateEmail(spec *Spec, value string, params map[string]interface{}) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+
+	if _, err := mail.ParseAddress(value); err != nil {
+		spec.Error = fmt.Sprintf("%q is not a valid email address", value)
+		return
+	}
+
+	spec.Valid = true
+}
+
+// validateDuration implements SpecNameDuration: value must parse with time.ParseDuration.
+func validateDuration(spec *Spec, value string, params map[string]interface{}) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+
+	if _, err := time.ParseDuration(value); err != nil {
+		spec.Error = fmt.Sprintf("%q is not a valid duration: %s", value, err)
+		return
+	}
+
+	spec.Valid = true
+}
+
+// validateBase64 implements SpecNameBase64: value must be standard base64.
+func validateBase64(spec *Spec, value string, params map[string]interface{}) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+
+	if _, err := base64.StdEncoding.DecodeString(value); err != nil {
+		spec.Error = fmt.Sprintf("%q is not valid base64: %s", value, err)
+		return
+	}
+
+	spec.Valid = true
+}
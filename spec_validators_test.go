@@ -0,0 +1,141 @@
+package godotenv
+
+import "testing"
+
+func TestValidateNumber(t *testing.T) {
+	cases := []struct {
+		value  string
+		params map[string]interface{}
+		valid  bool
+	}{
+		{"42", nil, true},
+		{"not-a-number", nil, false},
+		{"5", map[string]interface{}{"min": 10.0}, false},
+		{"15", map[string]interface{}{"min": 10.0}, true},
+		{"15", map[string]interface{}{"max": 10.0}, false},
+		{"10", map[string]interface{}{"min": 0.0, "max": 10.0}, true},
+		{"1.5", map[string]interface{}{"int": true}, false},
+		{"2", map[string]interface{}{"int": true}, true},
+	}
+
+	for _, c := range cases {
+		spec := Spec{}
+		validateNumber(&spec, c.value, c.params)
+		if spec.Valid != c.valid {
+			t.Errorf("validateNumber(%q, %v): Valid = %v, want %v (Error = %q)", c.value, c.params, spec.Valid, c.valid, spec.Error)
+		}
+	}
+}
+
+func TestValidateEnum(t *testing.T) {
+	params := map[string]interface{}{"values": []interface{}{"a", "b", "c"}}
+
+	spec := Spec{}
+	validateEnum(&spec, "b", params)
+	if !spec.Valid {
+		t.Errorf("expected %q to be a valid enum value, got error %q", "b", spec.Error)
+	}
+
+	spec = Spec{}
+	validateEnum(&spec, "z", params)
+	if spec.Valid {
+		t.Error("expected z to be invalid")
+	}
+}
+
+func TestValidateRegex(t *testing.T) {
+	params := map[string]interface{}{"pattern": `^\d+$`}
+
+	spec := Spec{}
+	validateRegex(&spec, "12345", params)
+	if !spec.Valid {
+		t.Errorf("expected match, got error %q", spec.Error)
+	}
+
+	spec = Spec{}
+	validateRegex(&spec, "abc", params)
+	if spec.Valid {
+		t.Error("expected non-digit value to fail")
+	}
+
+	spec = Spec{}
+	validateRegex(&spec, "abc", nil)
+	if spec.Valid || spec.Error == "" {
+		t.Error("expected an error when no pattern param is given")
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	spec := Spec{}
+	validateURL(&spec, "https://example.com/path", nil)
+	if !spec.Valid {
+		t.Errorf("expected valid URL, got error %q", spec.Error)
+	}
+
+	spec = Spec{}
+	validateURL(&spec, "not a url", nil)
+	if spec.Valid {
+		t.Error("expected invalid URL to fail")
+	}
+}
+
+func TestValidateEmail(t *testing.T) {
+	spec := Spec{}
+	validateEmail(&spec, "user@example.com", nil)
+	if !spec.Valid {
+		t.Errorf("expected valid email, got error %q", spec.Error)
+	}
+
+	spec = Spec{}
+	validateEmail(&spec, "not-an-email", nil)
+	if spec.Valid {
+		t.Error("expected invalid email to fail")
+	}
+}
+
+func TestValidateDuration(t *testing.T) {
+	spec := Spec{}
+	validateDuration(&spec, "30s", nil)
+	if !spec.Valid {
+		t.Errorf("expected valid duration, got error %q", spec.Error)
+	}
+
+	spec = Spec{}
+	validateDuration(&spec, "not-a-duration", nil)
+	if spec.Valid {
+		t.Error("expected invalid duration to fail")
+	}
+}
+
+func TestValidateBase64(t *testing.T) {
+	spec := Spec{}
+	validateBase64(&spec, "aGVsbG8=", nil)
+	if !spec.Valid {
+		t.Errorf("expected valid base64, got error %q", spec.Error)
+	}
+
+	spec = Spec{}
+	validateBase64(&spec, "not base64!!", nil)
+	if spec.Valid {
+		t.Error("expected invalid base64 to fail")
+	}
+}
+
+func TestRegisterSpecHandlerOverridesBuiltin(t *testing.T) {
+	orig := specsHandlers[SpecNameOpaque]
+	defer func() { specsHandlers[SpecNameOpaque] = orig }()
+
+	RegisterSpecHandler(SpecNameOpaque, func(spec *Spec, value string, params map[string]interface{}) {
+		spec.Valid = value == "allowed"
+	})
+
+	specs := GenerateSpecsFromComments(map[string]string{"FOO": "nope"}, map[string]string{"FOO": "Opaque"})
+	if specs["FOO"].Valid {
+		t.Error("expected custom handler to reject the value")
+	}
+
+	specs = GenerateSpecsFromComments(map[string]string{"FOO": "allowed"}, map[string]string{"FOO": "Opaque"})
+	if !specs["FOO"].Valid {
+		t.Error("expected custom handler to accept the value")
+	}
+}